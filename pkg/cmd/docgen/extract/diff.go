@@ -0,0 +1,35 @@
+package extract
+
+import "sort"
+
+// DialectDiff summarizes which dialects define each non-terminal, as
+// produced by Diff.
+type DialectDiff struct {
+	NonTerminals map[string][]string `json:"nonTerminals"`
+}
+
+// Diff computes a DialectDiff across a set of dialects' grammars, keyed by
+// dialect name. Rule names are compared post-normalization, i.e. after any
+// DialectSpec.Rename/Strip has already been applied to each Grammar.
+func Diff(grammars map[string]*Grammar) DialectDiff {
+	presence := make(map[string]map[string]bool)
+	for dialect, g := range grammars {
+		for _, name := range g.RuleNames() {
+			if presence[name] == nil {
+				presence[name] = make(map[string]bool)
+			}
+			presence[name][dialect] = true
+		}
+	}
+
+	diff := DialectDiff{NonTerminals: make(map[string][]string, len(presence))}
+	for name, dialects := range presence {
+		list := make([]string, 0, len(dialects))
+		for d := range dialects {
+			list = append(list, d)
+		}
+		sort.Strings(list)
+		diff.NonTerminals[name] = list
+	}
+	return diff
+}
@@ -0,0 +1,114 @@
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// FetchGrammar resolves repo+ref to a pinned commit via `git ls-remote`,
+// shallow-clones it into a per-SHA cache directory (reusing an existing
+// checkout when the SHA is already present), and returns the local path to
+// the requested grammar file along with the resolved commit SHA.
+func FetchGrammar(repo, ref, path string) (localPath string, sha string, err error) {
+	sha, resolvedRef, err := resolveRef(repo, ref)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving %s@%s: %v", repo, ref, err)
+	}
+
+	dir, err := cacheDir(repo, sha)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, statErr := os.Stat(dir); os.IsNotExist(statErr) {
+		if err := cloneInto(repo, resolvedRef, dir); err != nil {
+			return "", "", err
+		}
+	} else if statErr != nil {
+		return "", "", statErr
+	}
+
+	return filepath.Join(dir, path), sha, nil
+}
+
+// resolveRef shells out to `git ls-remote` to pin ref (a tag or branch
+// name, or "" for the "master" branch, falling back to "main" when there is
+// no "master") to an exact commit SHA. It
+// returns the SHA along with the branch or tag name that matched, since
+// ref may be empty.
+func resolveRef(repo, ref string) (sha string, resolvedRef string, err error) {
+	out, err := exec.Command("git", "ls-remote", "--heads", "--tags", repo).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("git ls-remote %s: %v", repo, err)
+	}
+
+	candidates := []string{"refs/tags/" + ref, "refs/heads/" + ref}
+	names := []string{ref, ref}
+	if ref == "" {
+		candidates = []string{"refs/heads/master", "refs/heads/main"}
+		names = []string{"master", "main"}
+	}
+
+	refs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	for i, c := range candidates {
+		// An annotated tag's own ref names the tag object; its peeled "^{}"
+		// entry names the commit it points at.
+		if sha, ok := refs[c+"^{}"]; ok {
+			return sha, names[i], nil
+		}
+		if sha, ok := refs[c]; ok {
+			return sha, names[i], nil
+		}
+	}
+	return "", "", fmt.Errorf("no tag or branch matching %q", ref)
+}
+
+// cacheDir returns the cache directory for a (repo, sha) pair, rooted at
+// $XDG_CACHE_HOME/cockroach-docgen (or ~/.cache/cockroach-docgen when
+// XDG_CACHE_HOME is unset).
+func cacheDir(repo, sha string) (string, error) {
+	u, err := url.Parse(repo)
+	if err != nil {
+		return "", fmt.Errorf("parsing grammar repo %q: %v", repo, err)
+	}
+
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".cache")
+	}
+
+	repoPath := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), ".git")
+	return filepath.Join(base, "cockroach-docgen", u.Host, repoPath+"@"+sha), nil
+}
+
+// cloneInto performs a shallow clone of repo at ref into dir.
+func cloneInto(repo, ref, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "clone", "--depth=1", "--branch", ref, repo, dir)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cloning %s@%s: %v", repo, ref, err)
+	}
+	return nil
+}
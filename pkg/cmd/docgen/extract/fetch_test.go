@@ -0,0 +1,74 @@
+package extract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setEnv sets the named environment variable (or unsets it, if value is
+// empty) and returns a func that restores its previous value.
+func setEnv(t *testing.T, name, value string) func() {
+	t.Helper()
+	prev, had := os.LookupEnv(name)
+	var err error
+	if value == "" {
+		err = os.Unsetenv(name)
+	} else {
+		err = os.Setenv(name, value)
+	}
+	if err != nil {
+		t.Fatalf("setting %s: %v", name, err)
+	}
+	return func() {
+		if had {
+			os.Setenv(name, prev)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	defer setEnv(t, "XDG_CACHE_HOME", "/cache")()
+
+	dir, err := cacheDir("https://github.com/pingcap/parser", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join("/cache", "cockroach-docgen", "github.com", "pingcap/parser@abc123")
+	if dir != want {
+		t.Errorf("got %q, want %q", dir, want)
+	}
+}
+
+func TestCacheDirStripsDotGitSuffix(t *testing.T) {
+	defer setEnv(t, "XDG_CACHE_HOME", "/cache")()
+
+	dir, err := cacheDir("https://github.com/pingcap/parser.git", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join("/cache", "cockroach-docgen", "github.com", "pingcap/parser@abc123")
+	if dir != want {
+		t.Errorf("got %q, want %q", dir, want)
+	}
+}
+
+func TestCacheDirFallsBackToHomeCache(t *testing.T) {
+	defer setEnv(t, "XDG_CACHE_HOME", "")()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory available: %v", err)
+	}
+
+	dir, err := cacheDir("https://github.com/pingcap/parser", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join(home, ".cache", "cockroach-docgen", "github.com", "pingcap/parser@abc123")
+	if dir != want {
+		t.Errorf("got %q, want %q", dir, want)
+	}
+}
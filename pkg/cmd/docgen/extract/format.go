@@ -0,0 +1,88 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+)
+
+// Format identifies an output rendering for a parsed Grammar.
+type Format int
+
+// The set of renderings Render supports.
+const (
+	// FormatBNF renders classic yacc-style BNF.
+	FormatBNF Format = iota
+	// FormatEBNF renders W3C-style EBNF.
+	FormatEBNF
+	// FormatMarkdown renders a Markdown reference, one section per
+	// non-terminal with cross-links between rules.
+	FormatMarkdown
+	// FormatRailroadJSON renders a JSON form suitable for railroad-diagram
+	// generators.
+	FormatRailroadJSON
+)
+
+// Ext returns the conventional file extension for the format, without a
+// leading dot.
+func (f Format) Ext() string {
+	switch f {
+	case FormatBNF:
+		return "bnf"
+	case FormatEBNF:
+		return "ebnf"
+	case FormatMarkdown:
+		return "md"
+	case FormatRailroadJSON:
+		return "json"
+	default:
+		return "txt"
+	}
+}
+
+// String implements flag.Value-style formatting and error messages.
+func (f Format) String() string {
+	switch f {
+	case FormatBNF:
+		return "bnf"
+	case FormatEBNF:
+		return "ebnf"
+	case FormatMarkdown:
+		return "markdown"
+	case FormatRailroadJSON:
+		return "railroad-json"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormat parses the --format flag values accepted by cmd/docgen.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "bnf":
+		return FormatBNF, nil
+	case "ebnf":
+		return FormatEBNF, nil
+	case "markdown":
+		return FormatMarkdown, nil
+	case "railroad-json":
+		return FormatRailroadJSON, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q", s)
+	}
+}
+
+// Render writes a rendering of g in the given format to w.
+func Render(g *Grammar, format Format, w io.Writer) error {
+	switch format {
+	case FormatBNF:
+		return renderBNF(g, w)
+	case FormatEBNF:
+		return renderEBNF(g, w)
+	case FormatMarkdown:
+		return renderMarkdown(g, w)
+	case FormatRailroadJSON:
+		return renderRailroadJSON(g, w)
+	default:
+		return fmt.Errorf("unknown format %v", format)
+	}
+}
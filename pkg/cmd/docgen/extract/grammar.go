@@ -0,0 +1,99 @@
+package extract
+
+// Grammar is the parsed form of a yacc grammar: an ordered list of rules,
+// each with one or more alternative productions.
+type Grammar struct {
+	Rules []*Rule
+}
+
+// Rule is a single named production (the left-hand side of a yacc rule)
+// together with its alternatives.
+type Rule struct {
+	Name         string
+	Comment      string
+	Alternatives []Alternative
+}
+
+// Alternative is one right-hand-side production of a Rule: an ordered
+// sequence of symbols.
+type Alternative struct {
+	Symbols []Symbol
+}
+
+// Symbol is a single element of an Alternative, either a reference to
+// another Rule (non-terminal) or a literal token (terminal).
+type Symbol struct {
+	Name     string
+	Terminal bool
+}
+
+// nonTerminals returns the set of rule names defined by the grammar, used
+// to distinguish terminals from non-terminals when rendering.
+func (g *Grammar) nonTerminals() map[string]bool {
+	set := make(map[string]bool, len(g.Rules))
+	for _, r := range g.Rules {
+		set[r.Name] = true
+	}
+	return set
+}
+
+// RuleByName returns the rule with the given name, or nil if there is none.
+func (g *Grammar) RuleByName(name string) *Rule {
+	for _, r := range g.Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// RuleNames returns the names of every rule in the grammar, in order.
+func (g *Grammar) RuleNames() []string {
+	names := make([]string, len(g.Rules))
+	for i, r := range g.Rules {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Apply returns a copy of g with rule and symbol names rewritten according
+// to rename, and any rules named in strip (along with alternatives
+// referencing them) removed. It is used to normalize productions across
+// dialects that share a common grammar ancestry but diverge in naming.
+func (g *Grammar) Apply(rename map[string]string, strip []string) *Grammar {
+	stripped := make(map[string]bool, len(strip))
+	for _, name := range strip {
+		stripped[name] = true
+	}
+	rewrite := func(name string) string {
+		if renamed, ok := rename[name]; ok {
+			return renamed
+		}
+		return name
+	}
+
+	out := &Grammar{}
+	for _, r := range g.Rules {
+		if stripped[r.Name] {
+			continue
+		}
+		newRule := &Rule{Name: rewrite(r.Name), Comment: r.Comment}
+		for _, alt := range r.Alternatives {
+			skip := false
+			symbols := make([]Symbol, 0, len(alt.Symbols))
+			for _, sym := range alt.Symbols {
+				if stripped[sym.Name] {
+					skip = true
+					break
+				}
+				symbols = append(symbols, Symbol{Name: rewrite(sym.Name), Terminal: sym.Terminal})
+			}
+			if skip {
+				continue
+			}
+			newRule.Alternatives = append(newRule.Alternatives, Alternative{Symbols: symbols})
+		}
+		out.Rules = append(out.Rules, newRule)
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package extract
+
+import "testing"
+
+func TestGrammarApply(t *testing.T) {
+	g := &Grammar{
+		Rules: []*Rule{
+			{
+				Name: "select_clause",
+				Alternatives: []Alternative{
+					{Symbols: []Symbol{{Name: "SELECT", Terminal: true}, {Name: "expr_list"}}},
+				},
+			},
+			{
+				Name: "expr_list",
+				Alternatives: []Alternative{
+					{Symbols: []Symbol{{Name: "expr"}}},
+				},
+			},
+			{
+				Name: "internal_only",
+				Alternatives: []Alternative{
+					{Symbols: []Symbol{{Name: "PRAGMA", Terminal: true}}},
+				},
+			},
+		},
+	}
+
+	out := g.Apply(map[string]string{"select_clause": "select_stmt"}, []string{"internal_only"})
+
+	if len(out.Rules) != 2 {
+		t.Fatalf("got %d rules, want 2: %+v", len(out.Rules), out.Rules)
+	}
+	if out.Rules[0].Name != "select_stmt" {
+		t.Errorf("got rule name %q, want %q", out.Rules[0].Name, "select_stmt")
+	}
+	if out.RuleByName("internal_only") != nil {
+		t.Errorf("expected internal_only to be stripped")
+	}
+}
+
+func TestGrammarApplyStripsReferencingAlternatives(t *testing.T) {
+	g := &Grammar{
+		Rules: []*Rule{
+			{
+				Name: "stmt",
+				Alternatives: []Alternative{
+					{Symbols: []Symbol{{Name: "select_stmt"}}},
+					{Symbols: []Symbol{{Name: "internal_only"}}},
+				},
+			},
+			{Name: "internal_only"},
+		},
+	}
+
+	out := g.Apply(nil, []string{"internal_only"})
+
+	stmt := out.RuleByName("stmt")
+	if stmt == nil {
+		t.Fatalf("expected stmt rule to survive")
+	}
+	if len(stmt.Alternatives) != 1 {
+		t.Fatalf("got %d alternatives, want 1: %+v", len(stmt.Alternatives), stmt.Alternatives)
+	}
+	if stmt.Alternatives[0].Symbols[0].Name != "select_stmt" {
+		t.Errorf("unexpected surviving alternative: %+v", stmt.Alternatives[0])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cockroach := &Grammar{Rules: []*Rule{{Name: "select_stmt"}, {Name: "insert_stmt"}}}
+	tidb := &Grammar{Rules: []*Rule{{Name: "select_stmt"}}}
+
+	diff := Diff(map[string]*Grammar{"cockroach": cockroach, "tidb": tidb})
+
+	if got, want := diff.NonTerminals["select_stmt"], []string{"cockroach", "tidb"}; !equalStrings(got, want) {
+		t.Errorf("select_stmt: got %v, want %v", got, want)
+	}
+	if got, want := diff.NonTerminals["insert_stmt"], []string{"cockroach"}; !equalStrings(got, want) {
+		t.Errorf("insert_stmt: got %v, want %v", got, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
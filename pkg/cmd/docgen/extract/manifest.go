@@ -0,0 +1,78 @@
+package extract
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// Manifest describes a batch of SQL dialects to document in one
+// invocation of cmd/docgen, as loaded from a --manifest YAML file.
+type Manifest struct {
+	Dialects []DialectSpec
+}
+
+// DialectSpec is one entry of a Manifest: where to find a dialect's
+// grammar, how to normalize its productions against the others, and where
+// to write its output.
+type DialectSpec struct {
+	// Name identifies the dialect, e.g. "cockroach", "tidb", "postgres".
+	Name string
+
+	// Path is a local .y grammar file. Exactly one of Path or Repo must be
+	// set.
+	Path string
+	// Repo, Ref, and GrammarPath fetch the grammar from a git remote, as
+	// with the --grammar-repo/--grammar-ref/--grammar-path flags.
+	Repo        string
+	Ref         string
+	GrammarPath string
+
+	// Rename maps production names to a normalized name shared across
+	// dialects, e.g. {"SelectStmt": "select_stmt"}.
+	Rename map[string]string
+	// Strip lists productions to drop entirely before rendering, along
+	// with any alternative that references them.
+	Strip []string
+
+	// OutputDir is the subdirectory (relative to the top-level
+	// --output-dir) this dialect's BNF/EBNF/Markdown are written to.
+	OutputDir string
+}
+
+// LoadManifest reads and parses a --manifest YAML file.
+func LoadManifest(filename string) (*Manifest, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %v", filename, err)
+	}
+	m, err := parseManifest(contents)
+	if err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %v", filename, err)
+	}
+	for i, d := range m.Dialects {
+		if d.Name == "" {
+			return nil, fmt.Errorf("dialect %d: name is required", i)
+		}
+		if (d.Path == "") == (d.Repo == "") {
+			return nil, fmt.Errorf("dialect %s: exactly one of path or repo must be set", d.Name)
+		}
+		if d.OutputDir == "" {
+			return nil, fmt.Errorf("dialect %s: outputDir is required", d.Name)
+		}
+	}
+	return m, nil
+}
+
+// Resolve locates the dialect's grammar file, fetching it from Repo/Ref
+// when Path is not set, and returns the local path together with a source
+// string ("" for local grammars) describing where it was fetched from.
+func (d DialectSpec) Resolve() (localPath string, source string, err error) {
+	if d.Path != "" {
+		return d.Path, "", nil
+	}
+	localPath, sha, err := FetchGrammar(d.Repo, d.Ref, d.GrammarPath)
+	if err != nil {
+		return "", "", err
+	}
+	return localPath, fmt.Sprintf("%s@%s", d.Repo, sha), nil
+}
@@ -0,0 +1,51 @@
+package extract
+
+import "testing"
+
+func TestParseManifest(t *testing.T) {
+	data := []byte(`
+dialects:
+  - name: cockroach
+    path: sql.y
+    outputDir: cockroach
+    rename:
+      select_clause: select_stmt
+    strip:
+      - internal_only
+  - name: tidb
+    repo: https://github.com/pingcap/parser
+    ref: v4.0.9
+    grammarPath: parser.y
+    outputDir: tidb
+`)
+
+	m, err := parseManifest(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Dialects) != 2 {
+		t.Fatalf("got %d dialects, want 2", len(m.Dialects))
+	}
+
+	cockroach := m.Dialects[0]
+	if cockroach.Name != "cockroach" || cockroach.Path != "sql.y" || cockroach.OutputDir != "cockroach" {
+		t.Errorf("unexpected cockroach dialect: %+v", cockroach)
+	}
+	if cockroach.Rename["select_clause"] != "select_stmt" {
+		t.Errorf("unexpected rename map: %+v", cockroach.Rename)
+	}
+	if len(cockroach.Strip) != 1 || cockroach.Strip[0] != "internal_only" {
+		t.Errorf("unexpected strip list: %+v", cockroach.Strip)
+	}
+
+	tidb := m.Dialects[1]
+	if tidb.Name != "tidb" || tidb.Repo != "https://github.com/pingcap/parser" || tidb.Ref != "v4.0.9" || tidb.GrammarPath != "parser.y" {
+		t.Errorf("unexpected tidb dialect: %+v", tidb)
+	}
+}
+
+func TestParseManifestRejectsMissingDialectsKey(t *testing.T) {
+	if _, err := parseManifest([]byte("foo: bar\n")); err == nil {
+		t.Fatal("expected an error for a manifest missing a top-level \"dialects:\" key")
+	}
+}
@@ -0,0 +1,145 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseManifest parses the narrow subset of YAML a --manifest file
+// actually uses: a top-level "dialects:" sequence of mappings with scalar
+// fields plus a nested "rename:" mapping and a nested "strip:" sequence.
+// This intentionally avoids pulling in a general-purpose YAML library
+// that nothing else in this tree depends on yet.
+func parseManifest(data []byte) (*Manifest, error) {
+	lines := manifestLines(data)
+	if len(lines) == 0 || lines[0].text != "dialects:" {
+		return nil, fmt.Errorf(`expected a top-level "dialects:" key`)
+	}
+
+	var m Manifest
+	i := 1
+	for i < len(lines) {
+		line := lines[i]
+		if !strings.HasPrefix(line.text, "- ") {
+			return nil, fmt.Errorf("line %d: expected a \"- \" dialect entry, got %q", line.num, line.text)
+		}
+		dialectIndent := line.indent
+		fieldIndent := dialectIndent + len("- ")
+
+		var d DialectSpec
+		if err := setDialectField(&d, strings.TrimPrefix(line.text, "- ")); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line.num, err)
+		}
+		i++
+
+		for i < len(lines) && lines[i].indent >= fieldIndent {
+			field := lines[i]
+			if field.indent != fieldIndent {
+				return nil, fmt.Errorf("line %d: unexpected indentation in %q", field.num, field.text)
+			}
+			switch field.text {
+			case "rename:":
+				i++
+				d.Rename = make(map[string]string)
+				for i < len(lines) && lines[i].indent > fieldIndent {
+					key, val, err := splitManifestKV(lines[i].text)
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %v", lines[i].num, err)
+					}
+					d.Rename[key] = val
+					i++
+				}
+			case "strip:":
+				i++
+				for i < len(lines) && lines[i].indent > fieldIndent {
+					if !strings.HasPrefix(lines[i].text, "- ") {
+						return nil, fmt.Errorf("line %d: expected a \"- \" strip entry, got %q", lines[i].num, lines[i].text)
+					}
+					d.Strip = append(d.Strip, unquoteManifestValue(strings.TrimPrefix(lines[i].text, "- ")))
+					i++
+				}
+			default:
+				if err := setDialectField(&d, field.text); err != nil {
+					return nil, fmt.Errorf("line %d: %v", field.num, err)
+				}
+				i++
+			}
+		}
+		m.Dialects = append(m.Dialects, d)
+	}
+	return &m, nil
+}
+
+// manifestLine is a single non-blank, non-comment line of a manifest
+// file, with its leading-space indentation measured and its original
+// line number preserved for error messages.
+type manifestLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+func manifestLines(data []byte) []manifestLine {
+	var lines []manifestLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := raw
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		lines = append(lines, manifestLine{num: i + 1, indent: indent, text: strings.TrimSpace(line)})
+	}
+	return lines
+}
+
+// setDialectField assigns a "key: value" line to the matching DialectSpec
+// field.
+func setDialectField(d *DialectSpec, text string) error {
+	key, val, err := splitManifestKV(text)
+	if err != nil {
+		return err
+	}
+	switch key {
+	case "name":
+		d.Name = val
+	case "path":
+		d.Path = val
+	case "repo":
+		d.Repo = val
+	case "ref":
+		d.Ref = val
+	case "grammarPath":
+		d.GrammarPath = val
+	case "outputDir":
+		d.OutputDir = val
+	default:
+		return fmt.Errorf("unknown dialect field %q", key)
+	}
+	return nil
+}
+
+// splitManifestKV splits a "key: value" line.
+func splitManifestKV(text string) (key, val string, err error) {
+	idx := strings.Index(text, ":")
+	if idx < 0 {
+		return "", "", fmt.Errorf("expected \"key: value\", got %q", text)
+	}
+	key = strings.TrimSpace(text[:idx])
+	val = unquoteManifestValue(text[idx+1:])
+	return key, val, nil
+}
+
+// unquoteManifestValue trims whitespace and a matching pair of quotes from
+// a scalar value.
+func unquoteManifestValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// rawRule is an intermediate representation used while scanning the
+// grammar, before alternatives are split into symbols.
+type rawRule struct {
+	name         string
+	comment      string
+	alternatives []string
+}
+
+// Parse reads the yacc grammar at filename and returns its parsed Grammar.
+func Parse(filename string) (*Grammar, error) {
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading grammar %s: %v", filename, err)
+	}
+	raw, err := parseRawRules(contents)
+	if err != nil {
+		return nil, fmt.Errorf("parsing grammar %s: %v", filename, err)
+	}
+	return buildGrammar(raw), nil
+}
+
+// GenerateBNF reads the yacc grammar at filename and returns a classic
+// BNF rendering of its rules. It is a convenience wrapper around Parse
+// and Render(FormatBNF).
+func GenerateBNF(filename string) ([]byte, error) {
+	g, err := Parse(filename)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := Render(g, FormatBNF, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseRawRules extracts the rule section (delimited by the first two "%%"
+// markers) of a yacc grammar and splits it into named rules, preserving
+// any comment immediately preceding a rule.
+func parseRawRules(contents []byte) ([]rawRule, error) {
+	sections := bytes.SplitN(contents, []byte("%%"), 3)
+	if len(sections) < 2 {
+		return nil, fmt.Errorf("grammar has no %%%% rule section")
+	}
+	body := sections[1]
+
+	var rules []rawRule
+	var cur *rawRule
+	var pendingComment string
+	var inComment bool
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		code, comments := extractLine(scanner.Text(), &inComment)
+		for _, c := range comments {
+			if cur == nil {
+				pendingComment = joinComment(pendingComment, c)
+			}
+		}
+		trimmed := strings.TrimSpace(code)
+		if trimmed == "" {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(trimmed, ":"):
+			if cur != nil {
+				rules = append(rules, *cur)
+			}
+			cur = &rawRule{name: strings.TrimSuffix(trimmed, ":"), comment: pendingComment}
+			pendingComment = ""
+		case trimmed == ";":
+			if cur != nil {
+				rules = append(rules, *cur)
+				cur = nil
+			}
+		case strings.HasPrefix(trimmed, "|"):
+			if cur != nil {
+				cur.alternatives = append(cur.alternatives, strings.TrimSpace(strings.TrimPrefix(trimmed, "|")))
+			}
+		default:
+			if cur != nil {
+				if len(cur.alternatives) == 0 {
+					cur.alternatives = append(cur.alternatives, trimmed)
+				} else {
+					last := len(cur.alternatives) - 1
+					cur.alternatives[last] = strings.TrimSpace(cur.alternatives[last] + " " + trimmed)
+				}
+			}
+		}
+	}
+	if cur != nil {
+		rules = append(rules, *cur)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// extractLine splits a single raw line into its non-comment code and any
+// "/* ... */" comment text found on it, carrying *inComment across calls
+// so that block comments spanning multiple lines are recognized in their
+// entirety rather than truncated at the first unclosed line. A line may
+// contain several comment/code spans (e.g. "a /* x */ b /* y */ c"), in
+// which case all of their comment text is returned in order.
+func extractLine(line string, inComment *bool) (code string, comments []string) {
+	for {
+		if *inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				comments = append(comments, strings.TrimSpace(line))
+				return code, comments
+			}
+			comments = append(comments, strings.TrimSpace(line[:end]))
+			line = line[end+2:]
+			*inComment = false
+			continue
+		}
+		start := strings.Index(line, "/*")
+		if start < 0 {
+			code += line
+			return code, comments
+		}
+		code += line[:start]
+		rest := line[start+2:]
+		end := strings.Index(rest, "*/")
+		if end < 0 {
+			comments = append(comments, strings.TrimSpace(rest))
+			*inComment = true
+			return code, comments
+		}
+		comments = append(comments, strings.TrimSpace(rest[:end]))
+		line = rest[end+2:]
+	}
+}
+
+// joinComment appends an additional comment line to an existing comment.
+func joinComment(existing, next string) string {
+	if next == "" {
+		return existing
+	}
+	if existing == "" {
+		return next
+	}
+	return existing + " " + next
+}
+
+// buildGrammar converts rawRules into a Grammar, splitting each
+// alternative into its constituent symbols and classifying each symbol as
+// a terminal or non-terminal.
+func buildGrammar(raw []rawRule) *Grammar {
+	names := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		names[r.name] = true
+	}
+
+	g := &Grammar{}
+	for _, r := range raw {
+		rule := &Rule{Name: r.name, Comment: r.comment}
+		for _, alt := range r.alternatives {
+			var symbols []Symbol
+			for _, tok := range strings.Fields(alt) {
+				symbols = append(symbols, Symbol{Name: tok, Terminal: !names[tok]})
+			}
+			rule.Alternatives = append(rule.Alternatives, Alternative{Symbols: symbols})
+		}
+		g.Rules = append(g.Rules, rule)
+	}
+	return g
+}
@@ -0,0 +1,103 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRawRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		grammar  string
+		expected []rawRule
+	}{
+		{
+			name: "simple rule with one alternative",
+			grammar: `
+%%
+stmt:
+  SELECT expr
+;
+%%
+`,
+			expected: []rawRule{
+				{name: "stmt", alternatives: []string{"SELECT expr"}},
+			},
+		},
+		{
+			name: "rule with multiple alternatives",
+			grammar: `
+%%
+stmt:
+  select_stmt
+| insert_stmt
+;
+%%
+`,
+			expected: []rawRule{
+				{name: "stmt", alternatives: []string{"select_stmt", "insert_stmt"}},
+			},
+		},
+		{
+			name: "single-line comment before a rule",
+			grammar: `
+%%
+/* stmt is a top-level statement. */
+stmt:
+  select_stmt
+;
+%%
+`,
+			expected: []rawRule{
+				{name: "stmt", comment: "stmt is a top-level statement.", alternatives: []string{"select_stmt"}},
+			},
+		},
+		{
+			name: "multi-line comment between alternatives is dropped, not merged into the production",
+			grammar: `
+%%
+stmt:
+  select_stmt
+/*
+ insert_stmt documented
+ across several lines
+ */
+| insert_stmt
+;
+%%
+`,
+			expected: []rawRule{
+				{name: "stmt", alternatives: []string{"select_stmt", "insert_stmt"}},
+			},
+		},
+		{
+			name: "multi-line comment before a rule is joined into its comment",
+			grammar: `
+%%
+/*
+ stmt is a top-level
+ statement.
+ */
+stmt:
+  select_stmt
+;
+%%
+`,
+			expected: []rawRule{
+				{name: "stmt", comment: "stmt is a top-level statement.", alternatives: []string{"select_stmt"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := parseRawRules([]byte(tc.grammar))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(rules, tc.expected) {
+				t.Errorf("got %#v, want %#v", rules, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+)
+
+// renderBNF writes g as classic yacc-style BNF.
+func renderBNF(g *Grammar, w io.Writer) error {
+	for _, r := range g.Rules {
+		if _, err := fmt.Fprintf(w, "%s ::=\n", r.Name); err != nil {
+			return err
+		}
+		for i, alt := range r.Alternatives {
+			sep := " "
+			if i > 0 {
+				sep = "|"
+			}
+			if _, err := fmt.Fprintf(w, "    %s %s\n", sep, joinSymbols(alt.Symbols)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinSymbols(symbols []Symbol) string {
+	s := ""
+	for i, sym := range symbols {
+		if i > 0 {
+			s += " "
+		}
+		s += sym.Name
+	}
+	return s
+}
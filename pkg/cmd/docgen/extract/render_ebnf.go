@@ -0,0 +1,23 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// renderEBNF writes g in W3C-style EBNF, e.g.:
+//
+//	rule ::= alt1 | alt2
+func renderEBNF(g *Grammar, w io.Writer) error {
+	for _, r := range g.Rules {
+		alts := make([]string, len(r.Alternatives))
+		for i, alt := range r.Alternatives {
+			alts[i] = joinSymbols(alt.Symbols)
+		}
+		if _, err := fmt.Fprintf(w, "%s ::= %s\n", r.Name, strings.Join(alts, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
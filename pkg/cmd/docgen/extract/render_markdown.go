@@ -0,0 +1,54 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// renderMarkdown writes g as a Markdown reference with one section per
+// non-terminal. References to other rules within a production are
+// rendered as links to their section.
+func renderMarkdown(g *Grammar, w io.Writer) error {
+	nonTerminals := g.nonTerminals()
+
+	if _, err := fmt.Fprintln(w, "# Grammar reference"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	for _, r := range g.Rules {
+		if _, err := fmt.Fprintf(w, "## %s\n\n", r.Name); err != nil {
+			return err
+		}
+		if r.Comment != "" {
+			if _, err := fmt.Fprintf(w, "%s\n\n", r.Comment); err != nil {
+				return err
+			}
+		}
+		for _, alt := range r.Alternatives {
+			if _, err := fmt.Fprintf(w, "- %s\n", markdownSymbols(alt.Symbols, nonTerminals)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownSymbols renders a production's symbols, linking any symbol that
+// names another rule to its section anchor.
+func markdownSymbols(symbols []Symbol, nonTerminals map[string]bool) string {
+	parts := make([]string, len(symbols))
+	for i, sym := range symbols {
+		if nonTerminals[sym.Name] {
+			parts[i] = fmt.Sprintf("[%s](#%s)", sym.Name, strings.ToLower(sym.Name))
+		} else {
+			parts[i] = fmt.Sprintf("`%s`", sym.Name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
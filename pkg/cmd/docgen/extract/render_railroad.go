@@ -0,0 +1,48 @@
+package extract
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// railroadSymbol is the JSON shape of a Symbol, suitable for consumption
+// by a railroad-diagram generator.
+type railroadSymbol struct {
+	Name     string `json:"name"`
+	Terminal bool   `json:"terminal"`
+}
+
+// railroadAlternative is the JSON shape of an Alternative.
+type railroadAlternative struct {
+	Symbols []railroadSymbol `json:"symbols"`
+}
+
+// railroadRule is the JSON shape of a Rule.
+type railroadRule struct {
+	Name         string                `json:"name"`
+	Comment      string                `json:"comment,omitempty"`
+	Alternatives []railroadAlternative `json:"alternatives"`
+}
+
+// renderRailroadJSON writes g as JSON describing each rule's alternatives
+// and symbols, suitable for feeding into a railroad-diagram generator.
+func renderRailroadJSON(g *Grammar, w io.Writer) error {
+	rules := make([]railroadRule, len(g.Rules))
+	for i, r := range g.Rules {
+		alts := make([]railroadAlternative, len(r.Alternatives))
+		for j, alt := range r.Alternatives {
+			symbols := make([]railroadSymbol, len(alt.Symbols))
+			for k, sym := range alt.Symbols {
+				symbols[k] = railroadSymbol{Name: sym.Name, Terminal: sym.Terminal}
+			}
+			alts[j] = railroadAlternative{Symbols: symbols}
+		}
+		rules[i] = railroadRule{Name: r.Name, Comment: r.Comment, Alternatives: alts}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Rules []railroadRule `json:"rules"`
+	}{Rules: rules})
+}
@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func testGrammar() *Grammar {
+	return &Grammar{
+		Rules: []*Rule{
+			{
+				Name: "stmt",
+				Alternatives: []Alternative{
+					{Symbols: []Symbol{{Name: "select_stmt"}}},
+					{Symbols: []Symbol{{Name: "SELECT", Terminal: true}, {Name: "expr", Terminal: true}}},
+				},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := testGrammar()
+
+	testCases := []struct {
+		format   Format
+		contains []string
+	}{
+		{FormatBNF, []string{"stmt ::=", "select_stmt", "SELECT expr"}},
+		{FormatEBNF, []string{"stmt ::= select_stmt | SELECT expr"}},
+		{FormatMarkdown, []string{"## stmt", "select_stmt", "SELECT"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(g, tc.format, &buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out := buf.String()
+			for _, want := range tc.contains {
+				if !bytes.Contains([]byte(out), []byte(want)) {
+					t.Errorf("output %q does not contain %q", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderRailroadJSON(t *testing.T) {
+	g := testGrammar()
+
+	var buf bytes.Buffer
+	if err := Render(g, FormatRailroadJSON, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Rules []struct {
+			Name         string `json:"name"`
+			Alternatives []struct {
+				Symbols []struct {
+					Name     string `json:"name"`
+					Terminal bool   `json:"terminal"`
+				} `json:"symbols"`
+			} `json:"alternatives"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded.Rules) != 1 || decoded.Rules[0].Name != "stmt" {
+		t.Fatalf("unexpected rules: %+v", decoded.Rules)
+	}
+	if len(decoded.Rules[0].Alternatives) != 2 {
+		t.Fatalf("unexpected alternatives: %+v", decoded.Rules[0].Alternatives)
+	}
+	second := decoded.Rules[0].Alternatives[1].Symbols
+	if len(second) != 2 || !second[0].Terminal || second[0].Name != "SELECT" {
+		t.Errorf("unexpected symbols: %+v", second)
+	}
+}
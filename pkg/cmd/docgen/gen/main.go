@@ -1,14 +1,298 @@
+// Command docgen extracts BNF grammar documentation from yacc-style SQL
+// grammar files.
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
 	"github.com/cockroachdb/cockroach/pkg/cmd/docgen/extract"
 )
 
+// formatList accumulates repeated --format flag values.
+type formatList []extract.Format
+
+func (f *formatList) String() string {
+	return fmt.Sprint([]extract.Format(*f))
+}
+
+func (f *formatList) Set(s string) error {
+	format, err := extract.ParseFormat(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, format)
+	return nil
+}
+
+var (
+	inputFile       = flag.String("input-file", "", "path to a single .y grammar file")
+	inputDir        = flag.String("input-dir", "", "directory to walk for *.y grammar files")
+	grammarRepo     = flag.String("grammar-repo", "", "git URL of a repo to fetch the grammar from, e.g. https://github.com/pingcap/parser")
+	grammarRef      = flag.String("grammar-ref", "", "tag or branch to fetch from --grammar-repo (defaults to master, or main if there is no master)")
+	grammarPath     = flag.String("grammar-path", "", "path of the .y grammar file within --grammar-repo")
+	manifest        = flag.String("manifest", "", "path of a grammars.yaml manifest describing multiple dialects to document in one run")
+	outputDir       = flag.String("output-dir", ".", "directory to write generated files to")
+	continueOnError = flag.Bool("continue-on-error", false, "log and skip grammars that fail to process instead of aborting")
+	debug           = flag.Bool("debug", false, "enable verbose logging")
+	formats         formatList
+)
+
+func init() {
+	flag.Var(&formats, "format", "output format to generate (bnf, ebnf, markdown, railroad-json); may be repeated")
+}
+
 func main() {
-	b, e := extract.GenerateBNF("/home/robi/Code/go/src/github.com/pingcap/parser/parser.y")
-	if e != nil {
-		panic(e)
+	flag.Parse()
+
+	if len(formats) == 0 {
+		formats = formatList{extract.FormatBNF}
+	}
+
+	if *manifest != "" {
+		if *inputFile != "" || *inputDir != "" || *grammarRepo != "" {
+			log.Fatal("--manifest cannot be combined with --input-file, --input-dir, or --grammar-repo")
+		}
+		runManifest(*manifest, *outputDir)
+		return
+	}
+
+	sources := 0
+	for _, set := range []bool{*inputFile != "", *inputDir != "", *grammarRepo != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		log.Fatal("exactly one of --input-file, --input-dir, --grammar-repo, or --manifest must be set")
+	}
+
+	var grammars []string
+	var source string
+	switch {
+	case *inputFile != "":
+		grammars = []string{*inputFile}
+	case *inputDir != "":
+		var err error
+		grammars, err = findGrammars(*inputDir)
+		if err != nil {
+			log.Fatalf("walking %s: %v", *inputDir, err)
+		}
+	default:
+		if *grammarPath == "" {
+			log.Fatal("--grammar-path is required with --grammar-repo")
+		}
+		localPath, sha, err := extract.FetchGrammar(*grammarRepo, *grammarRef, *grammarPath)
+		if err != nil {
+			log.Fatalf("fetching %s: %v", *grammarRepo, err)
+		}
+		grammars = []string{localPath}
+		source = fmt.Sprintf("%s@%s", *grammarRepo, sha)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		log.Fatalf("creating output dir %s: %v", *outputDir, err)
+	}
+
+	for _, grammar := range grammars {
+		if *debug {
+			log.Printf("processing %s", grammar)
+		}
+		if err := process(grammar, *outputDir, source); err != nil {
+			if *continueOnError {
+				log.Printf("skipping %s: %v", grammar, err)
+				continue
+			}
+			log.Fatalf("processing %s: %v", grammar, err)
+		}
 	}
-	fmt.Println(string(b))
+}
+
+// findGrammars walks dir looking for *.y grammar files.
+func findGrammars(dir string) ([]string, error) {
+	var grammars []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".y" {
+			grammars = append(grammars, path)
+		}
+		return nil
+	})
+	return grammars, err
+}
+
+// process parses a single grammar file and writes a rendering for each
+// requested format to <outputDir>/<basename>.<ext>. When source is
+// non-empty (the grammar was fetched via --grammar-repo), the BNF output
+// is prefixed with a "// source: <repo>@<sha>" header so generated docs
+// are reproducible.
+func process(grammar, outputDir, source string) error {
+	g, err := extract.Parse(grammar)
+	if err != nil {
+		return err
+	}
+	base := filepath.Base(grammar)
+	name := base[:len(base)-len(filepath.Ext(base))]
+	for _, format := range formats {
+		out := filepath.Join(outputDir, name+"."+format.Ext())
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("creating %s: %v", out, err)
+		}
+		if source != "" && format == extract.FormatBNF {
+			if _, err := fmt.Fprintf(f, "// source: %s\n\n", source); err != nil {
+				f.Close()
+				return fmt.Errorf("writing %s: %v", out, err)
+			}
+		}
+		err = extract.Render(g, format, f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing %s: %v", out, err)
+		}
+	}
+	return nil
+}
+
+// dialectFormats are the renderings written for each dialect in
+// --manifest mode. Railroad JSON is omitted since it's a per-grammar
+// artifact that diff.json/index.md don't cross-link.
+var dialectFormats = []extract.Format{extract.FormatBNF, extract.FormatEBNF, extract.FormatMarkdown}
+
+// runManifest implements --manifest: it documents every dialect named in
+// the manifest, then writes a top-level index.md and diff.json
+// summarizing them as a set.
+func runManifest(manifestPath, outputDir string) {
+	m, err := extract.LoadManifest(manifestPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		log.Fatalf("creating output dir %s: %v", outputDir, err)
+	}
+
+	grammars := make(map[string]*extract.Grammar)
+	var processed []extract.DialectSpec
+	for _, d := range m.Dialects {
+		if *debug {
+			log.Printf("processing dialect %s", d.Name)
+		}
+		g, source, err := resolveDialect(d)
+		if err != nil {
+			if *continueOnError {
+				log.Printf("skipping dialect %s: %v", d.Name, err)
+				continue
+			}
+			log.Fatalf("processing dialect %s: %v", d.Name, err)
+		}
+		if err := writeDialect(d, g, source, outputDir); err != nil {
+			if *continueOnError {
+				log.Printf("skipping dialect %s: %v", d.Name, err)
+				continue
+			}
+			log.Fatalf("writing dialect %s: %v", d.Name, err)
+		}
+		grammars[d.Name] = g
+		processed = append(processed, d)
+	}
+
+	if err := writeIndex(processed, outputDir); err != nil {
+		log.Fatalf("writing index.md: %v", err)
+	}
+	if err := writeDiff(grammars, outputDir); err != nil {
+		log.Fatalf("writing diff.json: %v", err)
+	}
+}
+
+// resolveDialect fetches (or reads) and normalizes a single dialect's
+// grammar.
+func resolveDialect(d extract.DialectSpec) (g *extract.Grammar, source string, err error) {
+	localPath, source, err := d.Resolve()
+	if err != nil {
+		return nil, "", err
+	}
+	g, err = extract.Parse(localPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return g.Apply(d.Rename, d.Strip), source, nil
+}
+
+// writeDialect writes a dialect's BNF/EBNF/Markdown renderings to
+// <outputDir>/<d.OutputDir>/<d.Name>.<ext>.
+func writeDialect(d extract.DialectSpec, g *extract.Grammar, source, outputDir string) error {
+	dir := filepath.Join(outputDir, d.OutputDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating output dir %s: %v", dir, err)
+	}
+	for _, format := range dialectFormats {
+		out := filepath.Join(dir, d.Name+"."+format.Ext())
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("creating %s: %v", out, err)
+		}
+		if source != "" && format == extract.FormatBNF {
+			if _, err := fmt.Fprintf(f, "// source: %s\n\n", source); err != nil {
+				f.Close()
+				return fmt.Errorf("writing %s: %v", out, err)
+			}
+		}
+		err = extract.Render(g, format, f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing %s: %v", out, err)
+		}
+	}
+	return nil
+}
+
+// writeIndex writes a top-level index.md cross-linking each dialect's
+// Markdown reference.
+func writeIndex(dialects []extract.DialectSpec, outputDir string) error {
+	out := filepath.Join(outputDir, "index.md")
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("creating %s: %v", out, err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, "# SQL dialect grammars"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f); err != nil {
+		return err
+	}
+	for _, d := range dialects {
+		link := filepath.Join(d.OutputDir, d.Name+".md")
+		if _, err := fmt.Fprintf(f, "- [%s](%s)\n", d.Name, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDiff writes diff.json, a machine-readable summary of which
+// non-terminals exist in which dialect.
+func writeDiff(grammars map[string]*extract.Grammar, outputDir string) error {
+	out := filepath.Join(outputDir, "diff.json")
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("creating %s: %v", out, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(extract.Diff(grammars))
 }